@@ -0,0 +1,64 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// Expression is a renderable SQL fragment with its own bound arguments,
+// used where the builder needs more than a bare table name - currently
+// only produced by As.
+type Expression interface {
+	WriteTo(w Writer) error
+}
+
+// aliasExpr aliases expr - a table name, a *Builder sub-select, or
+// another Expression - as alias.
+type aliasExpr struct {
+	expr  interface{}
+	alias string
+}
+
+var _ Expression = aliasExpr{}
+
+// As aliases expr as alias for use in From, Join/LeftJoin/RightJoin/
+// FullJoin and Select, e.g.
+//
+//	Select("u.id").From(As(Select("*").From("users").Where(Eq{"active": true}), "u")).
+//		LeftJoin(As("orders", "o"), "u.id = o.user_id")
+//
+// renders `FROM (SELECT * FROM users WHERE active=?) AS u LEFT JOIN
+// orders AS o ON u.id = o.user_id`.
+func As(expr interface{}, alias string) Expression {
+	return aliasExpr{expr: expr, alias: alias}
+}
+
+// WriteTo writes the SQL representation to Writer
+func (a aliasExpr) WriteTo(w Writer) error {
+	switch v := a.expr.(type) {
+	case *Builder:
+		if err := w.Write("("); err != nil {
+			return err
+		}
+		subSQL, subArgs, err := v.ToSQL()
+		if err != nil {
+			return err
+		}
+		if err := w.Write(subSQL, subArgs...); err != nil {
+			return err
+		}
+		if err := w.Write(")"); err != nil {
+			return err
+		}
+	case Expression:
+		if err := v.WriteTo(w); err != nil {
+			return err
+		}
+	case string:
+		if err := writeIdent(w, v); err != nil {
+			return err
+		}
+	default:
+		return ErrNotSupportType
+	}
+	return w.Write(" AS " + a.alias)
+}
@@ -0,0 +1,22 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAliasedSubSelectAndJoin(t *testing.T) {
+	sql, args, err := Postgres().Select("u.id").
+		From(As(Select("*").From("users").Where(Eq{"active": true}), "u")).
+		LeftJoin(As("orders", "o"), "u.id = o.user_id").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t,
+		"SELECT u.id FROM (SELECT * FROM users WHERE active=$1) AS u LEFT JOIN orders AS o ON u.id = o.user_id",
+		sql)
+	assert.EqualValues(t, []interface{}{true}, args)
+}
@@ -0,0 +1,537 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"database/sql"
+	"strconv"
+)
+
+type optype int
+
+const (
+	selectOp optype = iota
+	insertOp
+	updateOp
+	deleteOp
+)
+
+// joinPart is one JOIN clause of a SELECT or, for the dialects that
+// render multi-table UPDATE that way, of an UPDATE. table is a plain
+// table name or an As expression.
+type joinPart struct {
+	joinType joinType
+	table    interface{}
+	on       Cond
+}
+
+// Builder accumulates the pieces of a single SQL statement and renders
+// them with ToSQL/ToBoundSQL. Zero value is not usable; use Select,
+// From, Insert, Update, Delete or one of the dialect constructors
+// (MySQL, Postgres, ...) to obtain one.
+type Builder struct {
+	optype       optype
+	dialect      *Dialect
+	interpolator Interpolator
+	tableName    string
+	tableSource  interface{} // the value passed to From for the primary table: a string or an As expression
+	selects      []interface{}
+	cond         Cond
+	joins        []joinPart
+	fromTables   []string
+	updateMap    Eq
+	insertMap    Eq
+	conflict     *onConflict
+	ctes         []cte
+	orderBy      string
+	groupBy      string
+	having       string
+	limitN       int
+	offsetN      int
+}
+
+// NewBuilder creates an empty Builder with the generic `?` placeholder
+// dialect.
+func NewBuilder() *Builder {
+	return &Builder{optype: selectOp}
+}
+
+func (b *Builder) setDialect(d *Dialect) *Builder {
+	b.dialect = d
+	return b
+}
+
+// WithInterpolator overrides the Interpolator ToBoundSQL uses to render
+// bound arguments, in place of the dialect's default (see MySQL,
+// Postgres, ...).
+func (b *Builder) WithInterpolator(i Interpolator) *Builder {
+	b.interpolator = i
+	return b
+}
+
+// Select starts (or continues) a SELECT statement
+func Select(cols ...interface{}) *Builder {
+	return NewBuilder().Select(cols...)
+}
+
+// Select sets the selected columns, defaulting to `*` when none are
+// given. A column may be a plain string or an As expression, e.g.
+// Select(As("count(*)", "c")).
+func (b *Builder) Select(cols ...interface{}) *Builder {
+	b.optype = selectOp
+	b.selects = cols
+	return b
+}
+
+// From sets the table(s) a statement reads from or, for an UPDATE
+// builder against a dialect that renders multi-table UPDATE with a FROM
+// clause (Dialect.UseFromClauseForMultipleUpdateTables), the target
+// table followed by any additional tables the update reads from, e.g.
+// `Postgres().Update(eq).From("t1").From("t2")`. Against a JOIN-style
+// dialect (MySQL), a second From has no ON condition to render and is
+// rejected with ErrNotSupportDialect; use Join/LeftJoin/... instead. A
+// table may be a plain name or an As expression.
+func From(table interface{}) *Builder {
+	return NewBuilder().From(table)
+}
+
+// From implements the semantics documented on the package-level From.
+// A table may be a plain name or an As expression, e.g.
+// From(As(Select("*").From("users"), "u")).
+func (b *Builder) From(tables ...interface{}) *Builder {
+	for _, t := range tables {
+		if b.tableSource == nil {
+			b.tableSource = t
+			if name, ok := t.(string); ok {
+				b.tableName = name
+			}
+			continue
+		}
+		if name, ok := t.(string); ok {
+			b.fromTables = append(b.fromTables, name)
+		}
+	}
+	return b
+}
+
+// Join adds a JOIN clause. table may be a plain name or an As
+// expression, e.g. LeftJoin(As("orders", "o"), "u.id = o.user_id"). on
+// may be a Cond or a raw SQL string.
+func (b *Builder) Join(joinType joinType, table interface{}, on interface{}) *Builder {
+	var onCond Cond
+	switch v := on.(type) {
+	case Cond:
+		onCond = v
+	case string:
+		onCond = Expr(v)
+	}
+	b.joins = append(b.joins, joinPart{joinType: joinType, table: table, on: onCond})
+	return b
+}
+
+// LeftJoin adds a LEFT JOIN clause
+func (b *Builder) LeftJoin(table interface{}, on interface{}) *Builder {
+	return b.Join(leftJoin, table, on)
+}
+
+// RightJoin adds a RIGHT JOIN clause
+func (b *Builder) RightJoin(table interface{}, on interface{}) *Builder {
+	return b.Join(rightJoin, table, on)
+}
+
+// FullJoin adds a FULL JOIN clause
+func (b *Builder) FullJoin(table interface{}, on interface{}) *Builder {
+	return b.Join(fullJoin, table, on)
+}
+
+// InnerJoin adds an INNER JOIN clause
+func (b *Builder) InnerJoin(table interface{}, on interface{}) *Builder {
+	return b.Join(innerJoin, table, on)
+}
+
+// Where sets the statement's condition, replacing any previous one
+func Where(cond Cond) *Builder {
+	return NewBuilder().Where(cond)
+}
+
+// Where sets the statement's condition, replacing any previous one
+func (b *Builder) Where(cond Cond) *Builder {
+	b.cond = cond
+	return b
+}
+
+// And appends cond to the statement's condition with AND
+func (b *Builder) And(cond Cond) *Builder {
+	if b.cond == nil {
+		b.cond = cond
+	} else {
+		b.cond = b.cond.And(cond)
+	}
+	return b
+}
+
+// Or appends cond to the statement's condition with OR
+func (b *Builder) Or(cond Cond) *Builder {
+	if b.cond == nil {
+		b.cond = cond
+	} else {
+		b.cond = b.cond.Or(cond)
+	}
+	return b
+}
+
+// OrderBy sets the ORDER BY clause. order is written verbatim and is
+// never quoted by Builder.Quote - pass already-qualified or pre-quoted
+// identifiers if that matters for your dialect.
+func (b *Builder) OrderBy(order string) *Builder {
+	b.orderBy = order
+	return b
+}
+
+// GroupBy sets the GROUP BY clause. Like OrderBy, group is written
+// verbatim and is not affected by Builder.Quote.
+func (b *Builder) GroupBy(group string) *Builder {
+	b.groupBy = group
+	return b
+}
+
+// Having sets the HAVING clause. Like OrderBy, having is written
+// verbatim and is not affected by Builder.Quote.
+func (b *Builder) Having(having string) *Builder {
+	b.having = having
+	return b
+}
+
+// Limit sets LIMIT and, optionally, OFFSET
+func (b *Builder) Limit(limit int, offset ...int) *Builder {
+	b.limitN = limit
+	if len(offset) > 0 {
+		b.offsetN = offset[0]
+	}
+	return b
+}
+
+// Insert starts an INSERT statement
+func Insert(eq Eq) *Builder {
+	return NewBuilder().Insert(eq)
+}
+
+// Insert starts an INSERT statement
+func (b *Builder) Insert(eq Eq) *Builder {
+	b.optype = insertOp
+	b.insertMap = eq
+	return b
+}
+
+// Delete starts a DELETE statement
+func Delete(cond Cond) *Builder {
+	return NewBuilder().Delete(cond)
+}
+
+// Delete starts a DELETE statement
+func (b *Builder) Delete(cond Cond) *Builder {
+	b.optype = deleteOp
+	b.cond = cond
+	return b
+}
+
+// Update starts an UPDATE statement; eq holds the SET assignments
+func Update(eq Eq) *Builder {
+	return NewBuilder().Update(eq)
+}
+
+// Update starts an UPDATE statement; eq holds the SET assignments
+func (b *Builder) Update(eq Eq) *Builder {
+	b.optype = updateOp
+	b.updateMap = eq
+	return b
+}
+
+// writeSets writes `col=?,col2=?` SET assignments. Unlike Eq used as a
+// WHERE condition, a nil value here renders as the literal `col=null`
+// rather than `col IS NULL`, matching UPDATE assignment semantics. A
+// value wrapped with Raw, e.g. Eq{"c": Raw("t2.c")}, renders as that
+// unquoted expression instead of a bound placeholder - the only way to
+// assign another joined table's column, as opposed to the literal
+// string "t2.c".
+func writeSets(w Writer, eq Eq) error {
+	for i, k := range eq.sortedKeys() {
+		if i > 0 {
+			if err := w.Write(","); err != nil {
+				return err
+			}
+		}
+		name, _ := k.(string)
+		v := eq[k]
+		if err := writeIdent(w, name); err != nil {
+			return err
+		}
+		if v == nil {
+			if err := w.Write("=null"); err != nil {
+				return err
+			}
+			continue
+		}
+		if raw, ok := v.(rawExpr); ok {
+			if err := w.Write("=" + raw.s); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Write("=?", v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Builder) writeSelect(w Writer) error {
+	if err := w.Write("SELECT "); err != nil {
+		return err
+	}
+	if len(b.selects) == 0 {
+		if err := w.Write("*"); err != nil {
+			return err
+		}
+	} else {
+		for i, col := range b.selects {
+			if i > 0 {
+				if err := w.Write(","); err != nil {
+					return err
+				}
+			}
+			if err := writeIdent(w, col); err != nil {
+				return err
+			}
+		}
+	}
+	if err := w.Write(" FROM "); err != nil {
+		return err
+	}
+	if err := writeIdent(w, b.tableSource); err != nil {
+		return err
+	}
+	if err := b.writeJoins(w); err != nil {
+		return err
+	}
+	if err := b.writeWhere(w); err != nil {
+		return err
+	}
+	if b.groupBy != "" {
+		if err := w.Write(" GROUP BY " + b.groupBy); err != nil {
+			return err
+		}
+	}
+	if b.having != "" {
+		if err := w.Write(" HAVING " + b.having); err != nil {
+			return err
+		}
+	}
+	if b.orderBy != "" {
+		if err := w.Write(" ORDER BY " + b.orderBy); err != nil {
+			return err
+		}
+	}
+	if b.limitN > 0 {
+		if err := w.Write(" LIMIT " + strconv.Itoa(b.limitN)); err != nil {
+			return err
+		}
+		if b.offsetN > 0 {
+			if err := w.Write(" OFFSET " + strconv.Itoa(b.offsetN)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Builder) writeJoins(w Writer) error {
+	return writeJoins(w, b.joins)
+}
+
+func writeJoins(w Writer, joins []joinPart) error {
+	for _, j := range joins {
+		if err := w.Write(" " + j.joinType.String() + " "); err != nil {
+			return err
+		}
+		if err := writeIdent(w, j.table); err != nil {
+			return err
+		}
+		if err := w.Write(" ON "); err != nil {
+			return err
+		}
+		if j.on != nil {
+			if err := j.on.WriteTo(w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *Builder) writeWhere(w Writer) error {
+	return writeWhereCond(w, b.cond)
+}
+
+func writeWhereCond(w Writer, cond Cond) error {
+	if cond != nil && cond.IsValid() {
+		if err := w.Write(" WHERE "); err != nil {
+			return err
+		}
+		return cond.WriteTo(w)
+	}
+	return nil
+}
+
+func (b *Builder) writeInsert(w Writer) error {
+	if err := w.Write("INSERT INTO "); err != nil {
+		return err
+	}
+	if err := writeIdent(w, b.tableName); err != nil {
+		return err
+	}
+	if err := w.Write(" ("); err != nil {
+		return err
+	}
+	keys := b.insertMap.sortedKeys()
+	for i, k := range keys {
+		if i > 0 {
+			if err := w.Write(","); err != nil {
+				return err
+			}
+		}
+		name, _ := k.(string)
+		if err := writeIdent(w, name); err != nil {
+			return err
+		}
+	}
+	if err := w.Write(") VALUES ("); err != nil {
+		return err
+	}
+	for i, k := range keys {
+		if i > 0 {
+			if err := w.Write(","); err != nil {
+				return err
+			}
+		}
+		if err := w.Write("?", b.insertMap[k]); err != nil {
+			return err
+		}
+	}
+	return w.Write(")")
+}
+
+func (b *Builder) writeDelete(w Writer) error {
+	if err := w.Write("DELETE FROM "); err != nil {
+		return err
+	}
+	if err := writeIdent(w, b.tableName); err != nil {
+		return err
+	}
+	return b.writeWhere(w)
+}
+
+func (b *Builder) writeTo(w Writer) error {
+	if err := b.writeWith(w); err != nil {
+		return err
+	}
+	switch b.optype {
+	case selectOp:
+		return b.writeSelect(w)
+	case insertOp:
+		if b.conflict != nil && b.dialect != nil &&
+			(b.dialect.name == MSSQL || b.dialect.name == ORACLE) {
+			return b.writeMergeInto(w)
+		}
+		if err := b.writeInsert(w); err != nil {
+			return err
+		}
+		return b.writeOnConflict(w)
+	case updateOp:
+		return b.writeUpdate(w)
+	case deleteOp:
+		return b.writeDelete(w)
+	}
+	return ErrNotSupportType
+}
+
+// ToSQL renders the statement with `?` placeholders in the order
+// arguments were bound, converting to the builder's dialect if one was
+// set via MySQL/Postgres/MsSQL/Oracle/SQLite.
+func (b *Builder) ToSQL() (string, []interface{}, error) {
+	w := NewWriter()
+	w.dialect = b.dialect
+	if err := b.writeTo(w); err != nil {
+		return "", nil, err
+	}
+	rawSQL, args := w.String(), w.Args()
+
+	if b.dialect == nil {
+		return rawSQL, args, nil
+	}
+
+	if b.dialect.useNamedArgs {
+		return convertToNamedSQL(rawSQL, args, b.dialect.placeholderMark)
+	}
+	if b.dialect.placeholderMark != "?" {
+		convertedSQL, err := ConvertPlaceholder(rawSQL, b.dialect.placeholderMark)
+		if err != nil {
+			return "", nil, err
+		}
+		return convertedSQL, args, nil
+	}
+	return rawSQL, args, nil
+}
+
+// ToBoundSQL renders the statement with every argument interpolated
+// inline, for logging or debugging. It must never be sent to a driver
+// as-is since it bypasses parameter binding.
+func (b *Builder) ToBoundSQL() (string, error) {
+	w := NewWriter()
+	w.dialect = b.dialect
+	if err := b.writeTo(w); err != nil {
+		return "", err
+	}
+	interp := b.interpolator
+	if interp == nil {
+		interp = defaultInterpolator{}
+	}
+	return convertToBoundSQLWith(w.String(), w.Args(), interp)
+}
+
+// convertToNamedSQL rewrites `?` placeholders as `<prefix>N` (e.g. `@p1`,
+// `:p1`) and wraps each argument as a sql.NamedArg with that name,
+// unwrapping any argument that already was a sql.NamedArg or
+// sql.Named value.
+func convertToNamedSQL(rawSQL string, args []interface{}, prefix string) (string, []interface{}, error) {
+	parts, err := splitPlaceholders(rawSQL)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(args) < len(parts)-1 {
+		return "", nil, ErrNeedMoreArguments
+	}
+
+	w := NewWriter()
+	namedArgs := make([]interface{}, 0, len(parts)-1)
+	for i, p := range parts {
+		if err := w.Write(p); err != nil {
+			return "", nil, err
+		}
+		if i == len(parts)-1 {
+			continue
+		}
+		name := "p" + strconv.Itoa(i+1)
+		value := args[i]
+		if named, ok := value.(sql.NamedArg); ok {
+			value = named.Value
+		}
+		if err := w.Write(prefix + strconv.Itoa(i+1)); err != nil {
+			return "", nil, err
+		}
+		namedArgs = append(namedArgs, sql.Named(name, value))
+	}
+	return w.String(), namedArgs, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// Writer defines the interface a condition writes its SQL fragment and
+// arguments to.
+type Writer interface {
+	Write(sql string, args ...interface{}) error
+}
+
+// Cond defines an interface for a SQL condition which could be
+// calculated by `And`, `Or`
+type Cond interface {
+	WriteTo(w Writer) error
+	And(conds ...Cond) Cond
+	Or(conds ...Cond) Cond
+	IsValid() bool
+}
+
+type condEmpty struct{}
+
+var _ Cond = condEmpty{}
+
+// NewCond creates an empty condition
+func NewCond() Cond {
+	return condEmpty{}
+}
+
+func (condEmpty) WriteTo(w Writer) error {
+	return nil
+}
+
+func (condEmpty) And(conds ...Cond) Cond {
+	return And(append([]Cond{condEmpty{}}, conds...)...)
+}
+
+func (condEmpty) Or(conds ...Cond) Cond {
+	return Or(append([]Cond{condEmpty{}}, conds...)...)
+}
+
+func (condEmpty) IsValid() bool {
+	return false
+}
+
+// needsParentheses returns true when wrapping cond in a sub-expression
+// changes its meaning, e.g. an OR nested inside an AND.
+func needsParentheses(cond Cond) bool {
+	switch cond.(type) {
+	case condOr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Eq is a map condition that renders as `col1=? AND col2=? ...`, sorted
+// by key so generated SQL is deterministic. A nil value renders as
+// `col IS NULL`. A key wrapped with V (a Valuer) inverts the usual
+// roles: the key is bound as a placeholder and the value is treated as
+// the raw right-hand side, e.g. Eq{V("foo"): "column"} renders
+// `?=column` with "foo" bound - useful for `WHERE ?=ANY(col)`-style
+// expressions.
+type Eq map[interface{}]interface{}
+
+var _ Cond = Eq{}
+
+func (eq Eq) sortedKeys() []interface{} {
+	return sortedMapKeys(eq)
+}
+
+// WriteTo writes the SQL representation to Writer
+func (eq Eq) WriteTo(w Writer) error {
+	for i, k := range eq.sortedKeys() {
+		if i > 0 {
+			if err := w.Write(" AND "); err != nil {
+				return err
+			}
+		}
+		v := eq[k]
+		if val, ok := k.(Valuer); ok {
+			if err := w.Write("?=", val.Value()); err != nil {
+				return err
+			}
+			name, _ := v.(string)
+			if err := writeIdent(w, name); err != nil {
+				return err
+			}
+			continue
+		}
+		name, _ := k.(string)
+		if v == nil {
+			if err := writeIdent(w, name); err != nil {
+				return err
+			}
+			if err := w.Write(" IS NULL"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeIdent(w, name); err != nil {
+			return err
+		}
+		if err := w.Write("=?", v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// And implements Cond
+func (eq Eq) And(conds ...Cond) Cond {
+	return And(append([]Cond{eq}, conds...)...)
+}
+
+// Or implements Cond
+func (eq Eq) Or(conds ...Cond) Cond {
+	return Or(append([]Cond{eq}, conds...)...)
+}
+
+// IsValid implements Cond
+func (eq Eq) IsValid() bool {
+	return len(eq) > 0
+}
+
+// Neq is a map condition that renders as `col1<>? AND col2<>? ...`. It
+// supports V-wrapped keys the same way Eq does.
+type Neq map[interface{}]interface{}
+
+var _ Cond = Neq{}
+
+func (neq Neq) sortedKeys() []interface{} {
+	return sortedMapKeys(neq)
+}
+
+// WriteTo writes the SQL representation to Writer
+func (neq Neq) WriteTo(w Writer) error {
+	for i, k := range neq.sortedKeys() {
+		if i > 0 {
+			if err := w.Write(" AND "); err != nil {
+				return err
+			}
+		}
+		v := neq[k]
+		if val, ok := k.(Valuer); ok {
+			if err := w.Write("?<>", val.Value()); err != nil {
+				return err
+			}
+			name, _ := v.(string)
+			if err := writeIdent(w, name); err != nil {
+				return err
+			}
+			continue
+		}
+		name, _ := k.(string)
+		if v == nil {
+			if err := writeIdent(w, name); err != nil {
+				return err
+			}
+			if err := w.Write(" IS NOT NULL"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := writeIdent(w, name); err != nil {
+			return err
+		}
+		if err := w.Write("<>?", v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// And implements Cond
+func (neq Neq) And(conds ...Cond) Cond {
+	return And(append([]Cond{neq}, conds...)...)
+}
+
+// Or implements Cond
+func (neq Neq) Or(conds ...Cond) Cond {
+	return Or(append([]Cond{neq}, conds...)...)
+}
+
+// IsValid implements Cond
+func (neq Neq) IsValid() bool {
+	return len(neq) > 0
+}
+
+// sortedMapKeys returns m's keys sorted by their string representation,
+// so Eq/Neq render deterministically regardless of map iteration order.
+func sortedMapKeys(m map[interface{}]interface{}) []interface{} {
+	keys := make([]interface{}, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
@@ -0,0 +1,39 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// condExpr is a raw SQL condition with its own bound arguments, used for
+// fragments the builder has no dedicated type for, e.g. `Expr("a=? OR b=?", 1, 2)`.
+type condExpr struct {
+	sql  string
+	args []interface{}
+}
+
+var _ Cond = condExpr{}
+
+// Expr creates a raw SQL condition
+func Expr(sql string, args ...interface{}) Cond {
+	return condExpr{sql: sql, args: args}
+}
+
+// WriteTo writes the SQL representation to Writer
+func (expr condExpr) WriteTo(w Writer) error {
+	return w.Write(expr.sql, expr.args...)
+}
+
+// And implements Cond
+func (expr condExpr) And(conds ...Cond) Cond {
+	return And(append([]Cond{expr}, conds...)...)
+}
+
+// Or implements Cond
+func (expr condExpr) Or(conds ...Cond) Cond {
+	return Or(append([]Cond{expr}, conds...)...)
+}
+
+// IsValid implements Cond
+func (expr condExpr) IsValid() bool {
+	return len(expr.sql) > 0
+}
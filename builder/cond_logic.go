@@ -0,0 +1,110 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// condAnd joins its members with " AND ", parenthesizing any member
+// whose precedence would otherwise change (e.g. an OR).
+type condAnd []Cond
+
+var _ Cond = condAnd{}
+
+// And creates an AND condition, dropping any invalid sub-conditions
+func And(conds ...Cond) Cond {
+	var and condAnd
+	for _, cond := range conds {
+		if cond != nil && cond.IsValid() {
+			and = append(and, cond)
+		}
+	}
+	return and
+}
+
+// WriteTo writes the SQL representation to Writer
+func (and condAnd) WriteTo(w Writer) error {
+	for i, cond := range and {
+		if i > 0 {
+			if err := w.Write(" AND "); err != nil {
+				return err
+			}
+		}
+		if needsParentheses(cond) {
+			if err := w.Write("("); err != nil {
+				return err
+			}
+			if err := cond.WriteTo(w); err != nil {
+				return err
+			}
+			if err := w.Write(")"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := cond.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// And implements Cond
+func (and condAnd) And(conds ...Cond) Cond {
+	return And(append([]Cond{and}, conds...)...)
+}
+
+// Or implements Cond
+func (and condAnd) Or(conds ...Cond) Cond {
+	return Or(append([]Cond{and}, conds...)...)
+}
+
+// IsValid implements Cond
+func (and condAnd) IsValid() bool {
+	return len(and) > 0
+}
+
+// condOr joins its members with " OR "
+type condOr []Cond
+
+var _ Cond = condOr{}
+
+// Or creates an OR condition, dropping any invalid sub-conditions
+func Or(conds ...Cond) Cond {
+	var or condOr
+	for _, cond := range conds {
+		if cond != nil && cond.IsValid() {
+			or = append(or, cond)
+		}
+	}
+	return or
+}
+
+// WriteTo writes the SQL representation to Writer
+func (or condOr) WriteTo(w Writer) error {
+	for i, cond := range or {
+		if i > 0 {
+			if err := w.Write(" OR "); err != nil {
+				return err
+			}
+		}
+		if err := cond.WriteTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// And implements Cond
+func (or condOr) And(conds ...Cond) Cond {
+	return And(append([]Cond{or}, conds...)...)
+}
+
+// Or implements Cond
+func (or condOr) Or(conds ...Cond) Cond {
+	return Or(append([]Cond{or}, conds...)...)
+}
+
+// IsValid implements Cond
+func (or condOr) IsValid() bool {
+	return len(or) > 0
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// cte is one entry of a WITH clause: `name (col1,col2) AS (<sub>)`.
+type cte struct {
+	name      string
+	cols      []string
+	sub       *Builder
+	recursive bool
+}
+
+// With prepends a `WITH name AS (sub)` clause ahead of the statement,
+// for use as a table reference named name within it. cols, if given,
+// renders the CTE's explicit column list: `name (col1,col2) AS (sub)`.
+// Call With/WithRecursive repeatedly to chain multiple CTEs, rendered
+// comma-separated in call order.
+func (b *Builder) With(name string, sub *Builder, cols ...string) *Builder {
+	b.ctes = append(b.ctes, cte{name: name, cols: cols, sub: sub})
+	return b
+}
+
+// WithRecursive is like With but marks the CTE recursive, so the clause
+// renders as `WITH RECURSIVE` when any chained CTE needs it.
+func (b *Builder) WithRecursive(name string, sub *Builder, cols ...string) *Builder {
+	b.ctes = append(b.ctes, cte{name: name, cols: cols, sub: sub, recursive: true})
+	return b
+}
+
+// writeWith renders b's WITH clause, if any, ahead of the statement
+// writeTo goes on to write. Placeholders within each CTE's sub-query are
+// numbered in the same left-to-right pass as the rest of the statement,
+// so ToSQL's renumbering covers the combined SQL correctly.
+func (b *Builder) writeWith(w Writer) error {
+	if len(b.ctes) == 0 {
+		return nil
+	}
+	if b.dialect != nil && !b.dialect.SupportsWithCTE {
+		return ErrNotSupportDialect
+	}
+
+	recursive := false
+	for _, c := range b.ctes {
+		if c.recursive {
+			recursive = true
+			break
+		}
+	}
+	if recursive {
+		if err := w.Write("WITH RECURSIVE "); err != nil {
+			return err
+		}
+	} else {
+		if err := w.Write("WITH "); err != nil {
+			return err
+		}
+	}
+
+	for i, c := range b.ctes {
+		if i > 0 {
+			if err := w.Write(","); err != nil {
+				return err
+			}
+		}
+		if err := writeIdent(w, c.name); err != nil {
+			return err
+		}
+		if len(c.cols) > 0 {
+			if err := w.Write(" ("); err != nil {
+				return err
+			}
+			for j, col := range c.cols {
+				if j > 0 {
+					if err := w.Write(","); err != nil {
+						return err
+					}
+				}
+				if err := writeIdent(w, col); err != nil {
+					return err
+				}
+			}
+			if err := w.Write(")"); err != nil {
+				return err
+			}
+		}
+		if err := w.Write(" AS ("); err != nil {
+			return err
+		}
+		if err := c.sub.writeTo(w); err != nil {
+			return err
+		}
+		if err := w.Write(")"); err != nil {
+			return err
+		}
+	}
+
+	return w.Write(" ")
+}
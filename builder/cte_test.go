@@ -0,0 +1,67 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithCTEAndPlaceholderRenumbering(t *testing.T) {
+	activeUsers := Select("id").From("users").Where(Eq{"active": true})
+	sql, args, err := Postgres().With("u", activeUsers).
+		Select("*").From("u").Where(Eq{"id": 5}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH u AS (SELECT id FROM users WHERE active=$1) SELECT * FROM u WHERE id=$2", sql)
+	assert.EqualValues(t, []interface{}{true, 5}, args)
+}
+
+func TestWithColumnList(t *testing.T) {
+	sql, _, err := Postgres().With("t", Select("a", "b").From("src"), "x", "y").
+		Select("*").From("t").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH t (x,y) AS (SELECT a,b FROM src) SELECT * FROM t", sql)
+}
+
+func TestWithRecursive(t *testing.T) {
+	base := Select("1").From("dual").Where(Eq{"n": 1})
+	sql, args, err := Postgres().WithRecursive("tree", base).
+		Select("*").From("tree").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH RECURSIVE tree AS (SELECT 1 FROM dual WHERE n=$1) SELECT * FROM tree", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+}
+
+func TestWithMultipleChainedCTEs(t *testing.T) {
+	fromA := Select("id").From("a")
+	fromB := Select("id").From("b")
+	sql, _, err := Postgres().With("ca", fromA).With("cb", fromB).
+		Select("*").From("ca").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH ca AS (SELECT id FROM a),cb AS (SELECT id FROM b) SELECT * FROM ca", sql)
+}
+
+func TestWithAheadOfUpdateAndDelete(t *testing.T) {
+	limits := Select("id").From("quota").Where(Eq{"tier": "gold"})
+	sql, args, err := Postgres().With("q", limits).
+		Update(Eq{"plan": "gold"}).From("accounts").Where(In("id", Select("id").From("q"))).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH q AS (SELECT id FROM quota WHERE tier=$1) UPDATE accounts SET plan=$2 WHERE id IN (SELECT id FROM q)", sql)
+	assert.EqualValues(t, []interface{}{"gold", "gold"}, args)
+
+	sql, args, err = Postgres().With("q", limits).
+		Delete(In("id", Select("id").From("q"))).From("accounts").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "WITH q AS (SELECT id FROM quota WHERE tier=$1) DELETE FROM accounts WHERE id IN (SELECT id FROM q)", sql)
+	assert.EqualValues(t, []interface{}{"gold"}, args)
+}
+
+func TestWithUnsupportedDialectErrors(t *testing.T) {
+	d := newDialect(MYSQL, "?", false, false, "`", "`")
+	d.SupportsWithCTE = false
+	_, _, err := NewBuilder().setDialect(d).With("t", Select("1")).Select("*").From("t").ToSQL()
+	assert.EqualValues(t, ErrNotSupportDialect, err)
+}
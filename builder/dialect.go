@@ -0,0 +1,156 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// Dialect names supported by the builder
+const (
+	MYSQL    = "mysql"
+	POSTGRES = "postgres"
+	SQLITE   = "sqlite3"
+	MSSQL    = "mssql"
+	ORACLE   = "oracle"
+)
+
+// joinType enumerates the supported JOIN kinds
+type joinType int
+
+const (
+	innerJoin joinType = iota
+	leftJoin
+	rightJoin
+	fullJoin
+)
+
+func (j joinType) String() string {
+	switch j {
+	case leftJoin:
+		return "LEFT JOIN"
+	case rightJoin:
+		return "RIGHT JOIN"
+	case fullJoin:
+		return "FULL JOIN"
+	default:
+		return "JOIN"
+	}
+}
+
+// Dialect carries the rendering rules that differ between database
+// engines: how a bound argument's placeholder is written, and whether a
+// multi-table UPDATE is expressed via a FROM clause (Postgres, SQLite,
+// MsSQL) or a JOIN clause (MySQL).
+type Dialect struct {
+	name string
+
+	// placeholderMark is prepended to the 1-based argument index to
+	// build a placeholder, e.g. "$" -> "$1", "?" -> literal "?" for
+	// every argument (no numbering).
+	placeholderMark string
+
+	// useNamedArgs renders placeholders as markPlaceholderPrefix+"N"
+	// and returns args as sql.NamedArg{Name: "pN"} instead of raw
+	// values, as MsSQL (@p1) and Oracle (:p1) drivers expect.
+	useNamedArgs bool
+
+	// UseFromClauseForMultipleUpdateTables selects UPDATE ... FROM
+	// rendering (true) over UPDATE ... JOIN rendering (false) for a
+	// multi-table UPDATE. Oracle supports neither form natively, so a
+	// multi-table UPDATE against it is rejected with ErrNotSupportDialect.
+	UseFromClauseForMultipleUpdateTables bool
+
+	// quoteOpen/quoteClose bracket a table or column name quoted per
+	// this dialect's rules, e.g. "`"/"`" for MySQL, `"`/`"` for
+	// Postgres/SQLite/Oracle, "["/"]" for MsSQL.
+	quoteOpen, quoteClose string
+
+	// quote enables the identifier-quoting pass (see Builder.Quote).
+	// It defaults to false: existing callers that already pass
+	// pre-qualified or keyword-colliding expressions (e.g. raw
+	// "t1.id") keep rendering unquoted unless they opt in.
+	quote bool
+
+	// SupportsWithCTE reports whether this dialect accepts a `WITH`
+	// clause ahead of a statement. With/WithRecursive against a
+	// dialect with this false are rejected with ErrNotSupportDialect
+	// rather than silently dropping the clause.
+	SupportsWithCTE bool
+}
+
+func newDialect(name, placeholderMark string, useNamedArgs, useFromClause bool, quoteOpen, quoteClose string) *Dialect {
+	return &Dialect{
+		name:                                  name,
+		placeholderMark:                       placeholderMark,
+		useNamedArgs:                          useNamedArgs,
+		UseFromClauseForMultipleUpdateTables:  useFromClause,
+		quoteOpen:                             quoteOpen,
+		quoteClose:                            quoteClose,
+		SupportsWithCTE:                       true,
+	}
+}
+
+// MySQL returns a Builder pre-configured for MySQL's `?` placeholders
+// and UPDATE ... JOIN multi-table syntax.
+func MySQL() *Builder {
+	return NewBuilder().
+		setDialect(newDialect(MYSQL, "?", false, false, "`", "`")).
+		WithInterpolator(dialectInterpolator{dialectName: MYSQL})
+}
+
+// Postgres returns a Builder pre-configured for Postgres's `$N`
+// placeholders and UPDATE ... FROM multi-table syntax.
+func Postgres() *Builder {
+	return NewBuilder().
+		setDialect(newDialect(POSTGRES, "$", false, true, `"`, `"`)).
+		WithInterpolator(dialectInterpolator{dialectName: POSTGRES})
+}
+
+// SQLite returns a Builder pre-configured for SQLite's `?` placeholders
+// and UPDATE ... FROM multi-table syntax.
+func SQLite() *Builder {
+	return NewBuilder().
+		setDialect(newDialect(SQLITE, "?", false, true, `"`, `"`)).
+		WithInterpolator(dialectInterpolator{dialectName: SQLITE})
+}
+
+// MsSQL returns a Builder pre-configured for MsSQL's `@pN` named
+// placeholders and UPDATE ... FROM multi-table syntax.
+func MsSQL() *Builder {
+	return NewBuilder().
+		setDialect(newDialect(MSSQL, "@p", true, true, "[", "]")).
+		WithInterpolator(dialectInterpolator{dialectName: MSSQL})
+}
+
+// Oracle returns a Builder pre-configured for Oracle's `:pN` named
+// placeholders. Oracle has no UPDATE ... FROM/JOIN syntax, so a
+// multi-table UPDATE against it is rejected.
+func Oracle() *Builder {
+	return NewBuilder().
+		setDialect(newDialect(ORACLE, ":p", true, true, `"`, `"`)).
+		WithInterpolator(dialectInterpolator{dialectName: ORACLE})
+}
+
+// Quote turns on identifier quoting for table and column names rendered
+// from now on by Select, From, Update, Insert and Where, using the
+// dialect's quote character. It is a no-op on a Builder with no dialect
+// set. Use Quoted/Raw to override quoting on individual tokens. OrderBy,
+// GroupBy and Having take a free-form clause rather than a single
+// identifier and are unaffected - see OrderBy.
+func (b *Builder) Quote() *Builder {
+	if b.dialect != nil {
+		d := *b.dialect
+		d.quote = true
+		b.dialect = &d
+	}
+	return b
+}
+
+// NoQuote turns identifier quoting back off; see Quote.
+func (b *Builder) NoQuote() *Builder {
+	if b.dialect != nil {
+		d := *b.dialect
+		d.quote = false
+		b.dialect = &d
+	}
+	return b
+}
@@ -0,0 +1,18 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "errors"
+
+var (
+	// ErrNotSupportType not supported SQL type error
+	ErrNotSupportType = errors.New("not supported SQL type")
+	// ErrNoNotInConditions no NotIn ditions error
+	ErrNoNotInConditions = errors.New("No NotIn conditions")
+	// ErrNeedMoreArguments need more arguments error
+	ErrNeedMoreArguments = errors.New("need more arguments")
+	// ErrNotSupportDialect the dialect is not supported for the requested operation
+	ErrNotSupportDialect = errors.New("not supported dialect")
+)
@@ -0,0 +1,104 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Interpolator renders a single bound argument as a SQL literal for
+// ToBoundSQL/ConvertToBoundSQL. Implementations must escape anything
+// that isn't a numeric/bool literal, since the result is interpolated
+// directly into the statement text.
+type Interpolator interface {
+	Interpolate(buf *bytes.Buffer, arg interface{}) error
+}
+
+// defaultInterpolator renders the scalars, strings and sql.NamedArg
+// ConvertToBoundSQL has always supported. It's used when no dialect,
+// and so no more specific Interpolator, is set.
+type defaultInterpolator struct{}
+
+func (defaultInterpolator) Interpolate(buf *bytes.Buffer, arg interface{}) error {
+	return writeValue(buf, arg)
+}
+
+// dialectInterpolator extends defaultInterpolator with the type-aware
+// literal forms a real dialect needs for logging to be useful:
+// time.Time as a timestamp literal, []byte as a blob literal,
+// json.RawMessage as a quoted JSON string, driver.Valuer unwrapped via
+// Value(), and slices as a parenthesized tuple of interpolated elements.
+type dialectInterpolator struct {
+	dialectName string
+}
+
+func (di dialectInterpolator) Interpolate(buf *bytes.Buffer, arg interface{}) error {
+	switch v := arg.(type) {
+	case sql.NamedArg:
+		return di.Interpolate(buf, v.Value)
+	case time.Time:
+		return di.writeTime(buf, v)
+	case json.RawMessage:
+		return writeValue(buf, string(v))
+	case []byte:
+		return di.writeBytes(buf, v)
+	case driver.Valuer:
+		val, err := v.Value()
+		if err != nil {
+			return err
+		}
+		return di.Interpolate(buf, val)
+	}
+
+	if rv := reflect.ValueOf(arg); rv.IsValid() && rv.Kind() == reflect.Slice {
+		buf.WriteString("(")
+		for i := 0; i < rv.Len(); i++ {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			if err := di.Interpolate(buf, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		buf.WriteString(")")
+		return nil
+	}
+
+	return writeValue(buf, arg)
+}
+
+// writeTime renders t as the timestamp literal each dialect accepts.
+func (di dialectInterpolator) writeTime(buf *bytes.Buffer, t time.Time) error {
+	ts := t.Format("2006-01-02 15:04:05")
+	switch di.dialectName {
+	case POSTGRES, ORACLE:
+		buf.WriteString("TIMESTAMP '" + ts + "'")
+	default: // MySQL, SQLite, MsSQL
+		buf.WriteString("'" + ts + "'")
+	}
+	return nil
+}
+
+// writeBytes renders b as the blob literal each dialect accepts.
+func (di dialectInterpolator) writeBytes(buf *bytes.Buffer, b []byte) error {
+	hex := fmt.Sprintf("%x", b)
+	switch di.dialectName {
+	case POSTGRES:
+		buf.WriteString(`'\x` + hex + "'")
+	case MSSQL:
+		buf.WriteString("0x" + hex)
+	case ORACLE:
+		buf.WriteString("HEXTORAW('" + hex + "')")
+	default: // MySQL, SQLite
+		buf.WriteString("x'" + hex + "'")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeValuer struct{ n int }
+
+func (f fakeValuer) Value() (driver.Value, error) { return int64(f.n), nil }
+
+var _ driver.Valuer = fakeValuer{}
+
+func TestInterpolateTime(t *testing.T) {
+	ts := time.Date(2018, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	sql, err := MySQL().Insert(Eq{"created": ts}).From("tbl").ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (created) VALUES ('2018-01-02 15:04:05')", sql)
+
+	sql, err = Postgres().Insert(Eq{"created": ts}).From("tbl").ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (created) VALUES (TIMESTAMP '2018-01-02 15:04:05')", sql)
+}
+
+func TestInterpolateBytes(t *testing.T) {
+	b := []byte{0xde, 0xad, 0xbe, 0xef}
+
+	sql, err := MySQL().Insert(Eq{"data": b}).From("tbl").ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (data) VALUES (x'deadbeef')", sql)
+
+	sql, err = Postgres().Insert(Eq{"data": b}).From("tbl").ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, `INSERT INTO tbl (data) VALUES ('\xdeadbeef')`, sql)
+
+	sql, err = MsSQL().Insert(Eq{"data": b}).From("tbl").ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (data) VALUES (0xdeadbeef)", sql)
+}
+
+func TestInterpolateJSONRawMessage(t *testing.T) {
+	sql, err := MySQL().Insert(Eq{"meta": json.RawMessage(`{"a":1}`)}).From("tbl").ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, `INSERT INTO tbl (meta) VALUES ('{"a":1}')`, sql)
+}
+
+func TestInterpolateDriverValuerAndSlice(t *testing.T) {
+	// fakeValuer implements driver.Valuer, so this exercises the
+	// dialectInterpolator unwrapping it via Value() before interpolating
+	// the int64 it returns.
+	sql, err := MySQL().Select().From("tbl").Where(Eq{"id": fakeValuer{n: 7}}).ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM tbl WHERE id=7", sql)
+
+	// In expands a single slice value into one placeholder per element,
+	// so this must not double-wrap in parens via the interpolator's own
+	// slice handling.
+	sql, err = MySQL().Select().From("tbl").Where(In("id", []int{1, 2, 3})).ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM tbl WHERE id IN (1,2,3)", sql)
+}
+
+func TestWithInterpolatorOverride(t *testing.T) {
+	sql, err := MySQL().WithInterpolator(defaultInterpolator{}).
+		Select().From("tbl").Where(Eq{"id": 1}).ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM tbl WHERE id=1", sql)
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "strings"
+
+// rawExpr bypasses identifier quoting entirely, rendering verbatim.
+type rawExpr struct{ s string }
+
+// Raw marks expr as a raw SQL fragment that Builder.Quote must not
+// quote, e.g. Select(Raw("count(*) AS c")).
+func Raw(expr string) interface{} {
+	return rawExpr{s: expr}
+}
+
+// quotedIdent forces identifier quoting for name even when it would not
+// otherwise look like a plain dotted identifier.
+type quotedIdent struct{ name string }
+
+// Quoted forces name to be quoted per the dialect's rules regardless of
+// whether Builder.Quote is set, e.g. From(Quoted("order")).
+func Quoted(name string) interface{} {
+	return quotedIdent{name: name}
+}
+
+// isSimpleIdent reports whether name looks like a plain, possibly
+// dotted, identifier (`col` or `t.col`) as opposed to an expression,
+// wildcard or something already containing punctuation/parentheses that
+// quoting would break.
+func isSimpleIdent(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, part := range strings.Split(name, ".") {
+		if part == "" {
+			return false
+		}
+		for _, r := range part {
+			if r != '_' &&
+				(r < 'a' || r > 'z') &&
+				(r < 'A' || r > 'Z') &&
+				(r < '0' || r > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// quoteIdentifier wraps each dot-separated segment of name in d's quote
+// characters. With force=false, anything that doesn't look like a plain
+// identifier (isSimpleIdent) - an expression, a wildcard, an already
+// dotted+quoted token - is left untouched.
+func quoteIdentifier(d *Dialect, name string, force bool) string {
+	if name == "" || name == "*" || d == nil || !d.quote {
+		return name
+	}
+	if !force && !isSimpleIdent(name) {
+		return name
+	}
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = d.quoteOpen + p + d.quoteClose
+	}
+	return strings.Join(parts, ".")
+}
+
+// dialectOf recovers the Dialect a Writer was set up with, if any, so
+// Cond implementations (Eq, Neq, In, ...) can quote identifiers the same
+// way the Builder driving them would.
+func dialectOf(w Writer) *Dialect {
+	if dw, ok := w.(interface{ Dialect() *Dialect }); ok {
+		return dw.Dialect()
+	}
+	return nil
+}
+
+// writeIdent writes a table or column token - a plain string, a Quoted
+// or Raw marker, or an Expression (e.g. As(...)) - quoting plain
+// strings per w's dialect when quoting is enabled.
+func writeIdent(w Writer, token interface{}) error {
+	switch v := token.(type) {
+	case rawExpr:
+		return w.Write(v.s)
+	case quotedIdent:
+		return w.Write(quoteIdentifier(dialectOf(w), v.name, true))
+	case Expression:
+		return v.WriteTo(w)
+	case string:
+		return w.Write(quoteIdentifier(dialectOf(w), v, false))
+	default:
+		return ErrNotSupportType
+	}
+}
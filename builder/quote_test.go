@@ -0,0 +1,83 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	sql2 "database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteIdentifiersPerDialect(t *testing.T) {
+	sql, args, err := MySQL().Quote().Select("a", "b").From("table1").Where(Eq{"a": 1}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `a`,`b` FROM `table1` WHERE `a`=?", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	sql, args, err = Postgres().Quote().Select("a").From("table1").Where(Eq{"a": 1}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "a" FROM "table1" WHERE "a"=$1`, sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	sql, args, err = MsSQL().Quote().Select("a").From("table1").Where(Eq{"a": 1}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT [a] FROM [table1] WHERE [a]=@p1", sql)
+	assert.EqualValues(t, []interface{}{sql2.Named("p1", 1)}, args)
+}
+
+func TestQuoteDottedIdentifier(t *testing.T) {
+	sql, _, err := Postgres().Quote().Select("t.a").From("table1").Where(Eq{"t.a": 1}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "t"."a" FROM "table1" WHERE "t"."a"=$1`, sql)
+}
+
+func TestNoQuoteByDefault(t *testing.T) {
+	sql, _, err := MySQL().Select("a").From("table1").Where(Eq{"a": 1}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT a FROM table1 WHERE a=?", sql)
+}
+
+func TestQuotedAndRawOverrides(t *testing.T) {
+	// Raw bypasses quoting even with Quote() enabled.
+	sql, _, err := MySQL().Quote().Select(Raw("count(*) AS c")).From("t1").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT count(*) AS c FROM `t1`", sql)
+
+	// Quoted forces quoting of a name that wouldn't otherwise qualify as
+	// a plain identifier, once Quote() is enabled.
+	sql, _, err = MySQL().Quote().Select("a").From(Quoted("t1 AS t")).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `a` FROM `t1 AS t`", sql)
+}
+
+func TestQuoteValuerKeyedEqNeq(t *testing.T) {
+	sql, args, err := MySQL().Quote().Select().From("tbl").Where(Eq{V("foo"): "col"}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM `tbl` WHERE ?=`col`", sql)
+	assert.EqualValues(t, []interface{}{"foo"}, args)
+
+	sql, args, err = MySQL().Quote().Select().From("tbl").Where(Neq{V("foo"): "col"}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT * FROM `tbl` WHERE ?<>`col`", sql)
+	assert.EqualValues(t, []interface{}{"foo"}, args)
+}
+
+func TestQuoteDoesNotAffectOrderGroupHaving(t *testing.T) {
+	// OrderBy/GroupBy/Having take a free-form clause, not a single
+	// identifier, so Builder.Quote leaves them untouched even when it
+	// quotes the columns in Select.
+	sql, _, err := MySQL().Quote().Select("a").From("table1").
+		GroupBy("a").Having("count(*) > 1").OrderBy("a DESC").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "SELECT `a` FROM `table1` GROUP BY a HAVING count(*) > 1 ORDER BY a DESC", sql)
+}
+
+func TestQuoteAsIdentifier(t *testing.T) {
+	sql, _, err := Postgres().Quote().Select("u.id").From("users").
+		LeftJoin(As("orders", "o"), "u.id = o.user_id").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, `SELECT "u"."id" FROM "users" LEFT JOIN "orders" AS o ON u.id = o.user_id`, sql)
+}
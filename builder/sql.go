@@ -0,0 +1,159 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// splitPlaceholders splits sql around each unquoted `?`, returning one
+// more element than there are placeholders. A `?` inside a single-quoted
+// string literal (including a backslash-escaped quote) is left alone.
+func splitPlaceholders(sql string) ([]string, error) {
+	var parts []string
+	var last int
+	var inQuote bool
+	for i := 0; i < len(sql); i++ {
+		switch sql[i] {
+		case '\'':
+			if inQuote && i > 0 && sql[i-1] == '\\' {
+				continue
+			}
+			inQuote = !inQuote
+		case '?':
+			if !inQuote {
+				parts = append(parts, sql[last:i])
+				last = i + 1
+			}
+		}
+	}
+	parts = append(parts, sql[last:])
+	return parts, nil
+}
+
+// ConvertPlaceholder replaces every unquoted `?` in sql with mark
+// followed by its 1-based position, e.g. ConvertPlaceholder(sql, "$")
+// turns `a=? AND b=?` into `a=$1 AND b=$2`.
+func ConvertPlaceholder(sql, mark string) (string, error) {
+	parts, err := splitPlaceholders(sql)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	for i, p := range parts {
+		buf.WriteString(p)
+		if i < len(parts)-1 {
+			buf.WriteString(mark)
+			buf.WriteString(strconv.Itoa(i + 1))
+		}
+	}
+	return buf.String(), nil
+}
+
+// noSQLQuoteNeeded reports whether a value's default formatting is
+// already valid, unquoted SQL (numbers, bool). Anything else, including
+// nil, must go through quoting or a dedicated literal before it can be
+// interpolated.
+func noSQLQuoteNeeded(a interface{}) bool {
+	switch a.(type) {
+	case int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64,
+		float32, float64, bool:
+		return true
+	}
+	return false
+}
+
+// writeValue appends arg's SQL literal representation to buf, doubling
+// single quotes in strings so the result is safe to interpolate.
+func writeValue(buf *bytes.Buffer, arg interface{}) error {
+	switch v := arg.(type) {
+	case sql.NamedArg:
+		return writeValue(buf, v.Value)
+	case nil:
+		buf.WriteString("null")
+	case string:
+		buf.WriteString("'")
+		buf.WriteString(strings.Replace(v, "'", "''", -1))
+		buf.WriteString("'")
+	default:
+		if noSQLQuoteNeeded(v) {
+			fmt.Fprintf(buf, "%v", v)
+		} else {
+			fmt.Fprintf(buf, "'%v'", v)
+		}
+	}
+	return nil
+}
+
+// ConvertToBoundSQL interpolates args into sql's `?` placeholders in
+// order, returning a statement suitable for logging. It must not be sent
+// to a driver, since it bypasses parameter binding. It uses
+// defaultInterpolator; Builder.ToBoundSQL uses the builder's dialect
+// Interpolator instead (see Builder.WithInterpolator).
+func ConvertToBoundSQL(sql string, args []interface{}) (string, error) {
+	return convertToBoundSQLWith(sql, args, defaultInterpolator{})
+}
+
+// convertToBoundSQLWith is ConvertToBoundSQL parameterized on the
+// Interpolator used to render each bound argument.
+func convertToBoundSQLWith(sql string, args []interface{}, interp Interpolator) (string, error) {
+	parts, err := splitPlaceholders(sql)
+	if err != nil {
+		return "", err
+	}
+	if len(args) < len(parts)-1 {
+		return "", ErrNeedMoreArguments
+	}
+
+	var buf bytes.Buffer
+	for i, p := range parts {
+		buf.WriteString(p)
+		if i < len(parts)-1 {
+			if err := interp.Interpolate(&buf, args[i]); err != nil {
+				return "", err
+			}
+		}
+	}
+	return buf.String(), nil
+}
+
+// ToSQL renders cond, which must be a *Builder or a Cond, with `?`
+// placeholders and the arguments bound to them in order.
+func ToSQL(cond interface{}) (string, []interface{}, error) {
+	switch c := cond.(type) {
+	case *Builder:
+		return c.ToSQL()
+	case Cond:
+		w := NewWriter()
+		if err := c.WriteTo(w); err != nil {
+			return "", nil, err
+		}
+		return w.String(), w.Args(), nil
+	default:
+		return "", nil, ErrNotSupportType
+	}
+}
+
+// ToBoundSQL renders cond, which must be a *Builder or a Cond, with its
+// arguments interpolated inline for logging.
+func ToBoundSQL(cond interface{}) (string, error) {
+	switch c := cond.(type) {
+	case *Builder:
+		return c.ToBoundSQL()
+	case Cond:
+		w := NewWriter()
+		if err := c.WriteTo(w); err != nil {
+			return "", err
+		}
+		return ConvertToBoundSQL(w.String(), w.Args())
+	default:
+		return "", ErrNotSupportType
+	}
+}
@@ -95,6 +95,28 @@ func TestSQL(t *testing.T) {
 	assert.EqualValues(t, "a IN (?,?)", newSQL)
 	assert.EqualValues(t, []interface{}{1, 2}, args)
 
+	// A single slice value is equivalent to passing its elements
+	// individually, both in the rendered placeholders and in args - a
+	// driver can't bind a Go slice as one parameter.
+	newSQL, args, err = ToSQL(In("a", []int{1, 2}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, "a IN (?,?)", newSQL)
+	assert.EqualValues(t, []interface{}{1, 2}, args)
+
+	// []byte is a single blob value, not a list, so it isn't expanded.
+	newSQL, args, err = ToSQL(In("a", []byte("ab")))
+	assert.NoError(t, err)
+	assert.EqualValues(t, "a IN (?)", newSQL)
+	assert.EqualValues(t, []interface{}{[]byte("ab")}, args)
+
+	// An empty slice has no elements to expand into; IN () is invalid
+	// SQL on every dialect, so it falls back to binding the empty slice
+	// as a single arg rather than generating invalid SQL text.
+	newSQL, args, err = ToSQL(In("a", []int{}))
+	assert.NoError(t, err)
+	assert.EqualValues(t, "a IN (?)", newSQL)
+	assert.EqualValues(t, []interface{}{[]int{}}, args)
+
 	newSQL, args, err = ToSQL(Select("id").From("table").Where(In("a", 1, 2)))
 	assert.NoError(t, err)
 	assert.EqualValues(t, "SELECT id FROM table WHERE a IN (?,?)", newSQL)
@@ -158,3 +180,45 @@ func TestToSQLInjectionHarmlessDisposal(t *testing.T) {
 	assert.EqualValues(t, "UPDATE table1 SET a=?,b=null", sql)
 	assert.EqualValues(t, []interface{}{1}, args)
 }
+
+func TestMultiTableUpdateFrom(t *testing.T) {
+	// c is set to t2's column, not the literal string "t2.c", so it must
+	// be wrapped in Raw to render unquoted and unbound.
+	sql, args, err := Postgres().Update(Eq{"c": Raw("t2.c")}).From("t1").From("t2").Where(Eq{"t1.id": 1}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE t1 SET c=t2.c FROM t2 WHERE t1.id=$1", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	sql, args, err = SQLite().Update(Eq{"c": Raw("t2.c")}).From("t1").From("t2").Where(Eq{"t1.id": 1}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE t1 SET c=t2.c FROM t2 WHERE t1.id=?", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+
+	sql, args, err = MySQL().Update(Eq{"c": Raw("t2.c")}).From("t1").Join(innerJoin, "t2", Expr("t1.id=t2.id")).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE t1 JOIN t2 ON t1.id=t2.id SET c=t2.c", sql)
+	assert.EqualValues(t, []interface{}(nil), args)
+
+	_, _, err = Oracle().Update(Eq{"c": Raw("t2.c")}).From("t1").From("t2").ToSQL()
+	assert.EqualValues(t, ErrNotSupportDialect, err)
+
+	// MySQL renders multi-table UPDATE via JOIN, which needs an ON
+	// condition a bare From("t2") doesn't supply; it must be rejected
+	// rather than silently dropped.
+	_, _, err = MySQL().Update(Eq{"c": Raw("t2.c")}).From("t1").From("t2").ToSQL()
+	assert.EqualValues(t, ErrNotSupportDialect, err)
+
+	// The mirror image: a FROM-style dialect given a Join but no
+	// explicit second From("t2"). A FROM list can't open with a bare
+	// JOIN/ON, so t2 stands in as the first FROM item and the join's ON
+	// condition folds into WHERE.
+	sql, args, err = Postgres().Update(Eq{"c": Raw("t2.c")}).From("t1").LeftJoin("t2", "t1.id=t2.id").ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE t1 SET c=t2.c FROM t2 WHERE t1.id=t2.id", sql)
+	assert.EqualValues(t, []interface{}(nil), args)
+
+	sql, args, err = Postgres().Update(Eq{"c": Raw("t2.c")}).From("t1").LeftJoin("t2", "t1.id=t2.id").Where(Eq{"t1.active": true}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "UPDATE t1 SET c=t2.c FROM t2 WHERE t1.active=$1 AND t1.id=t2.id", sql)
+	assert.EqualValues(t, []interface{}{true}, args)
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// writeUpdate renders the UPDATE statement. A single-table UPDATE
+// (no extra From tables or Joins) renders as plain
+// `UPDATE t1 SET ...`. A multi-table UPDATE renders per the builder's
+// dialect: `UPDATE t1 SET c=t2.c FROM t2 WHERE ...` for Postgres, SQLite
+// and MsSQL (Dialect.UseFromClauseForMultipleUpdateTables), or
+// `UPDATE t1 JOIN t2 ON ... SET t1.c=t2.c WHERE ...` for MySQL. Oracle
+// has no multi-table UPDATE syntax and rejects one with
+// ErrNotSupportDialect. A JOIN-style dialect has no FROM-clause table
+// list to render a bare From("t2") into, so one given without a
+// matching Join is rejected the same way rather than silently dropped.
+// On a FROM-style dialect, a FROM list can't open with a bare JOIN/ON,
+// so a Join given without an explicit From("t2") has its table stand in
+// as the first FROM item and its ON condition folded into WHERE
+// instead.
+func (b *Builder) writeUpdate(w Writer) error {
+	if err := w.Write("UPDATE "); err != nil {
+		return err
+	}
+	if err := writeIdent(w, b.tableName); err != nil {
+		return err
+	}
+
+	multiTable := len(b.fromTables) > 0 || len(b.joins) > 0
+	if multiTable && b.dialect != nil && b.dialect.name == ORACLE {
+		return ErrNotSupportDialect
+	}
+
+	joinStyle := multiTable && b.dialect != nil && !b.dialect.UseFromClauseForMultipleUpdateTables
+	if joinStyle {
+		if len(b.fromTables) > 0 {
+			return ErrNotSupportDialect
+		}
+		if err := b.writeJoins(w); err != nil {
+			return err
+		}
+		if err := w.Write(" SET "); err != nil {
+			return err
+		}
+		if err := writeSets(w, b.updateMap); err != nil {
+			return err
+		}
+		return b.writeWhere(w)
+	}
+
+	if err := w.Write(" SET "); err != nil {
+		return err
+	}
+	if err := writeSets(w, b.updateMap); err != nil {
+		return err
+	}
+
+	whereCond := b.cond
+	if multiTable {
+		if err := w.Write(" FROM "); err != nil {
+			return err
+		}
+		wroteFromItem := false
+		for i, t := range b.fromTables {
+			if i > 0 {
+				if err := w.Write(","); err != nil {
+					return err
+				}
+			}
+			if err := writeIdent(w, t); err != nil {
+				return err
+			}
+			wroteFromItem = true
+		}
+
+		joins := b.joins
+		if !wroteFromItem && len(joins) > 0 {
+			first := joins[0]
+			if err := writeIdent(w, first.table); err != nil {
+				return err
+			}
+			if first.on != nil {
+				if whereCond == nil {
+					whereCond = first.on
+				} else {
+					whereCond = whereCond.And(first.on)
+				}
+			}
+			joins = joins[1:]
+		}
+		if err := writeJoins(w, joins); err != nil {
+			return err
+		}
+	}
+
+	return writeWhereCond(w, whereCond)
+}
@@ -0,0 +1,281 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// onConflict holds the upsert clause attached to an Insert builder via
+// OnConflict/OnConflictConstraint. target and constraint are mutually
+// exclusive, as are doNothing and doUpdate; the last one set wins.
+type onConflict struct {
+	target     []string
+	constraint string
+	doNothing  bool
+	doUpdate   Eq
+	updateCond Cond
+}
+
+// excludedValue renders as the portable "value that would have been
+// inserted for this column" placeholder: `EXCLUDED.col` on Postgres and
+// SQLite, `VALUES(col)` on MySQL.
+type excludedValue struct {
+	col string
+}
+
+// Excluded refers to the value that would have been inserted for col,
+// for use on the right-hand side of an OnConflict().DoUpdate(Eq{...})
+// assignment, e.g. DoUpdate(Eq{"total": Excluded("total")}).
+func Excluded(col string) interface{} {
+	return excludedValue{col: col}
+}
+
+// OnConflict starts an upsert clause on an Insert builder, triggered by
+// a conflict on the given columns; it renders `ON CONFLICT (col1,col2)
+// ...` (or the MySQL/MsSQL/Oracle equivalent). Call DoNothing or
+// DoUpdate on the result to complete the clause. Use
+// OnConflictConstraint instead to target a named constraint rather than
+// columns.
+func (b *Builder) OnConflict(target ...string) *Builder {
+	b.conflict = &onConflict{target: target}
+	return b
+}
+
+// OnConflictConstraint starts an upsert clause triggered by a conflict
+// caught by the named constraint; it renders `ON CONFLICT ON CONSTRAINT
+// name ...` on Postgres and SQLite. MySQL's `ON DUPLICATE KEY UPDATE`
+// has no concept of a named constraint and ignores it, matching on any
+// unique key the same as OnConflict does. MsSQL/Oracle render the
+// upsert as a MERGE INTO matched on explicit columns, which a
+// constraint name alone cannot supply, so ToSQL fails with
+// ErrNotSupportDialect for those dialects. Call DoNothing or DoUpdate on
+// the result to complete the clause.
+func (b *Builder) OnConflictConstraint(name string) *Builder {
+	b.conflict = &onConflict{constraint: name}
+	return b
+}
+
+// DoNothing makes the upsert a no-op on conflict (`ON CONFLICT DO NOTHING`
+// / `INSERT IGNORE`-equivalent rendering per dialect).
+func (b *Builder) DoNothing() *Builder {
+	if b.conflict != nil {
+		b.conflict.doNothing = true
+	}
+	return b
+}
+
+// DoUpdate sets the assignments applied on conflict, e.g.
+// Insert(eq).OnConflict("id").DoUpdate(Eq{"name": Excluded("name")}).
+// cond, if given, renders as a WHERE clause restricting when the update
+// applies (Postgres/SQLite only).
+func (b *Builder) DoUpdate(set Eq, cond ...Cond) *Builder {
+	if b.conflict == nil {
+		return b
+	}
+	b.conflict.doUpdate = set
+	if len(cond) > 0 {
+		b.conflict.updateCond = cond[0]
+	}
+	return b
+}
+
+func writeExcludedAware(w Writer, dialectName string, eq Eq) error {
+	for i, k := range eq.sortedKeys() {
+		if i > 0 {
+			if err := w.Write(","); err != nil {
+				return err
+			}
+		}
+		name, _ := k.(string)
+		v := eq[k]
+		if ex, ok := v.(excludedValue); ok {
+			var ref string
+			if dialectName == MYSQL {
+				ref = "VALUES(" + ex.col + ")"
+			} else {
+				ref = "EXCLUDED." + ex.col
+			}
+			if err := w.Write(name + "=" + ref); err != nil {
+				return err
+			}
+			continue
+		}
+		if v == nil {
+			if err := w.Write(name + "=null"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := w.Write(name+"=?", v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeOnConflict appends the upsert clause for b.conflict, if any, to
+// an already-written INSERT statement. MsSQL and Oracle are handled
+// separately by writeMergeInto before writeInsert ever runs, since a
+// MERGE INTO replaces the whole statement rather than trailing it.
+func (b *Builder) writeOnConflict(w Writer) error {
+	c := b.conflict
+	if c == nil {
+		return nil
+	}
+
+	dialectName := ""
+	if b.dialect != nil {
+		dialectName = b.dialect.name
+	}
+
+	switch dialectName {
+	case MYSQL:
+		if c.doNothing {
+			keys := b.insertMap.sortedKeys()
+			if len(keys) == 0 {
+				return ErrNotSupportDialect
+			}
+			firstCol, _ := keys[0].(string)
+			return w.Write(" ON DUPLICATE KEY UPDATE " + firstCol + "=" + firstCol)
+		}
+		if err := w.Write(" ON DUPLICATE KEY UPDATE "); err != nil {
+			return err
+		}
+		return writeExcludedAware(w, dialectName, c.doUpdate)
+
+	default: // Postgres, SQLite and the generic/no-dialect builder
+		if err := w.Write(" ON CONFLICT"); err != nil {
+			return err
+		}
+		switch {
+		case c.constraint != "":
+			if err := w.Write(" ON CONSTRAINT " + c.constraint); err != nil {
+				return err
+			}
+		case len(c.target) > 0:
+			if err := w.Write(" (" + joinStrings(c.target) + ")"); err != nil {
+				return err
+			}
+		}
+		if c.doNothing || len(c.doUpdate) == 0 {
+			return w.Write(" DO NOTHING")
+		}
+		if err := w.Write(" DO UPDATE SET "); err != nil {
+			return err
+		}
+		if err := writeExcludedAware(w, dialectName, c.doUpdate); err != nil {
+			return err
+		}
+		if c.updateCond != nil && c.updateCond.IsValid() {
+			if err := w.Write(" WHERE "); err != nil {
+				return err
+			}
+			return c.updateCond.WriteTo(w)
+		}
+		return nil
+	}
+}
+
+func joinStrings(cols []string) string {
+	out := ""
+	for i, c := range cols {
+		if i > 0 {
+			out += ","
+		}
+		out += c
+	}
+	return out
+}
+
+// writeMergeInto renders the MsSQL/Oracle upsert as a MERGE INTO
+// statement, matching rows on the conflict target.
+func (b *Builder) writeMergeInto(w Writer) error {
+	c := b.conflict
+	if len(c.target) == 0 {
+		// MERGE INTO matches rows via an explicit column comparison; a
+		// constraint name alone (OnConflictConstraint) gives no columns
+		// to build that comparison from.
+		return ErrNotSupportDialect
+	}
+	if err := w.Write("MERGE INTO "); err != nil {
+		return err
+	}
+	if err := writeIdent(w, b.tableName); err != nil {
+		return err
+	}
+	if err := w.Write(" AS target USING (SELECT "); err != nil {
+		return err
+	}
+	keys := b.insertMap.sortedKeys()
+	colNames := make([]string, len(keys))
+	for i, k := range keys {
+		name, _ := k.(string)
+		colNames[i] = name
+		if i > 0 {
+			if err := w.Write(","); err != nil {
+				return err
+			}
+		}
+		if err := w.Write("? AS "+name, b.insertMap[k]); err != nil {
+			return err
+		}
+	}
+	if err := w.Write(") AS source ON ("); err != nil {
+		return err
+	}
+	for i, k := range c.target {
+		if i > 0 {
+			if err := w.Write(" AND "); err != nil {
+				return err
+			}
+		}
+		if err := w.Write("target." + k + "=source." + k); err != nil {
+			return err
+		}
+	}
+	if err := w.Write(")"); err != nil {
+		return err
+	}
+	if !c.doNothing && len(c.doUpdate) > 0 {
+		if err := w.Write(" WHEN MATCHED THEN UPDATE SET "); err != nil {
+			return err
+		}
+		for i, k := range c.doUpdate.sortedKeys() {
+			if i > 0 {
+				if err := w.Write(","); err != nil {
+					return err
+				}
+			}
+			name, _ := k.(string)
+			v := c.doUpdate[k]
+			if ex, ok := v.(excludedValue); ok {
+				if err := w.Write(name + "=source." + ex.col); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := w.Write(name+"=?", v); err != nil {
+				return err
+			}
+		}
+	}
+	if err := w.Write(" WHEN NOT MATCHED THEN INSERT ("); err != nil {
+		return err
+	}
+	if err := w.Write(joinStrings(colNames)); err != nil {
+		return err
+	}
+	if err := w.Write(") VALUES ("); err != nil {
+		return err
+	}
+	for i, name := range colNames {
+		if i > 0 {
+			if err := w.Write(","); err != nil {
+				return err
+			}
+		}
+		if err := w.Write("source." + name); err != nil {
+			return err
+		}
+	}
+	return w.Write(")")
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnConflictDoUpdate(t *testing.T) {
+	sql, args, err := Postgres().Insert(Eq{"id": 1, "name": "a"}).From("tbl").
+		OnConflict("id").DoUpdate(Eq{"name": Excluded("name")}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (id,name) VALUES ($1,$2) ON CONFLICT (id) DO UPDATE SET name=EXCLUDED.name", sql)
+	assert.EqualValues(t, []interface{}{1, "a"}, args)
+
+	sql, args, err = MySQL().Insert(Eq{"id": 1, "name": "a"}).From("tbl").
+		OnConflict("id").DoUpdate(Eq{"name": Excluded("name")}).ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (id,name) VALUES (?,?) ON DUPLICATE KEY UPDATE name=VALUES(name)", sql)
+	assert.EqualValues(t, []interface{}{1, "a"}, args)
+}
+
+func TestOnConflictDoNothing(t *testing.T) {
+	sql, _, err := SQLite().Insert(Eq{"id": 1}).From("tbl").OnConflict("id").DoNothing().ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (id) VALUES (?) ON CONFLICT (id) DO NOTHING", sql)
+}
+
+func TestOnConflictConstraint(t *testing.T) {
+	sql, _, err := Postgres().Insert(Eq{"id": 1}).From("tbl").
+		OnConflictConstraint("users_email_key").DoNothing().ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (id) VALUES ($1) ON CONFLICT ON CONSTRAINT users_email_key DO NOTHING", sql)
+
+	// MySQL has no notion of a named constraint and ignores it, matching
+	// on any unique key the same as OnConflict.
+	sql, _, err = MySQL().Insert(Eq{"id": 1}).From("tbl").
+		OnConflictConstraint("users_email_key").DoNothing().ToSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t, "INSERT INTO tbl (id) VALUES (?) ON DUPLICATE KEY UPDATE id=id", sql)
+
+	// MERGE INTO needs explicit columns to match rows on; a constraint
+	// name alone can't supply them.
+	_, err = MsSQL().Insert(Eq{"id": 1}).From("tbl").
+		OnConflictConstraint("users_email_key").DoNothing().ToBoundSQL()
+	assert.EqualValues(t, ErrNotSupportDialect, err)
+}
+
+func TestOnConflictDoNothingEmptyInsert(t *testing.T) {
+	// An empty insert map is valid input and must not panic.
+	_, _, err := MySQL().Insert(Eq{}).From("tbl").OnConflict("id").DoNothing().ToSQL()
+	assert.EqualValues(t, ErrNotSupportDialect, err)
+}
+
+func TestOnConflictMergeInto(t *testing.T) {
+	sql, err := MsSQL().Insert(Eq{"id": 1, "name": "a"}).From("tbl").
+		OnConflict("id").DoUpdate(Eq{"name": Excluded("name")}).ToBoundSQL()
+	assert.NoError(t, err)
+	assert.EqualValues(t,
+		"MERGE INTO tbl AS target USING (SELECT 1 AS id,'a' AS name) AS source ON (target.id=source.id) WHEN MATCHED THEN UPDATE SET name=source.name WHEN NOT MATCHED THEN INSERT (id,name) VALUES (source.id,source.name)",
+		sql)
+}
@@ -0,0 +1,41 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+// Valuer marks a value that should be bound as a placeholder rather
+// than treated as a raw column/table identifier, so it can be used
+// wherever Eq, Neq, Lt and friends normally expect an identifier, e.g.
+// Eq{V("foo"): "column"} renders as `?=column` with "foo" bound.
+type Valuer interface {
+	Value() interface{}
+}
+
+// value is the concrete Valuer returned by V
+type value struct {
+	v interface{}
+}
+
+var _ Valuer = value{}
+
+// V wraps v so it can appear on the left-hand side of a condition as a
+// bound placeholder instead of a raw identifier, e.g.
+// Eq{V("foo"): "column"}, Lt{V(5): "table.qty"}, In(V(1), sub).
+func V(v interface{}) Valuer {
+	return value{v: v}
+}
+
+func (val value) Value() interface{} {
+	return val.v
+}
+
+// writeSide writes side as either a bound placeholder (if it is a
+// Valuer) or an identifier (otherwise), quoting it per w's dialect via
+// writeIdent.
+func writeSide(w Writer, side interface{}) error {
+	if v, ok := side.(Valuer); ok {
+		return w.Write("?", v.Value())
+	}
+	return writeIdent(w, side)
+}
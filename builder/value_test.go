@@ -0,0 +1,23 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValuerOnLeftHandSide(t *testing.T) {
+	sql, args, err := ToSQL(Eq{V("foo"): "column"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, "?=column", sql)
+	assert.EqualValues(t, []interface{}{"foo"}, args)
+
+	sql, args, err = ToSQL(In(V(1), Select("id").From("t")))
+	assert.NoError(t, err)
+	assert.EqualValues(t, "? IN (SELECT id FROM t)", sql)
+	assert.EqualValues(t, []interface{}{1}, args)
+}
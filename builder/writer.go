@@ -0,0 +1,44 @@
+// Copyright 2018 The Xorm Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package builder
+
+import "bytes"
+
+// BytesWriter implements Writer, collecting the rendered SQL fragment
+// and its bound arguments in declaration order. Its optional dialect
+// lets a Cond being written (Eq, Neq, In, ...) quote identifiers the
+// same way the Builder driving it would, without the Cond interface
+// itself needing to know about dialects.
+type BytesWriter struct {
+	*bytes.Buffer
+	args    []interface{}
+	dialect *Dialect
+}
+
+var _ Writer = &BytesWriter{}
+
+// NewWriter creates a BytesWriter
+func NewWriter() *BytesWriter {
+	return &BytesWriter{Buffer: &bytes.Buffer{}}
+}
+
+// Dialect returns the writer's dialect, or nil if none was set
+func (w *BytesWriter) Dialect() *Dialect {
+	return w.dialect
+}
+
+// Write appends sql to the buffer and args to the argument list
+func (w *BytesWriter) Write(sql string, args ...interface{}) error {
+	if _, err := w.Buffer.WriteString(sql); err != nil {
+		return err
+	}
+	w.args = append(w.args, args...)
+	return nil
+}
+
+// Args returns the arguments collected so far
+func (w *BytesWriter) Args() []interface{} {
+	return w.args
+}